@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd builds the `config` command group, used to manage
+// .clockify-cli.yaml without hand-editing it.
+func NewConfigCmd(deps App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage clockify-cli's configuration file",
+	}
+
+	cmd.AddCommand(NewConfigProfileCmd(deps))
+	cmd.AddCommand(NewConfigInitCmd(deps))
+
+	return cmd
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+
+	clockify "github.com/lucassabreu/clockify-cli/http"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+)
+
+// user is the subset of the Clockify /user response NewConfigInitCmd needs.
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+// workspace is the subset of a Clockify /workspaces entry NewConfigInitCmd
+// needs.
+type workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NewConfigInitCmd builds the `config init` command, which walks the user
+// through generating .clockify-cli.yaml interactively.
+func NewConfigInitCmd(deps App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "Interactively generate the clockify-cli config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token := ""
+			if err := survey.AskOne(
+				&survey.Password{Message: "Clockify API token:"},
+				&token,
+				survey.WithValidator(survey.Required),
+			); err != nil {
+				return err
+			}
+			deps.Viper.Set(TOKEN, token)
+
+			c, err := deps.HTTPClient()
+			if err != nil {
+				return err
+			}
+
+			u, err := discoverUser(c)
+			if err != nil {
+				return err
+			}
+			deps.Viper.Set(USER_ID, u.ID)
+			fmt.Fprintf(cmd.OutOrStdout(), "discovered user id %s (%s)\n", u.ID, u.Email)
+
+			workspaces, err := listWorkspaces(c)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(workspaces))
+			byName := make(map[string]workspace, len(workspaces))
+			for _, w := range workspaces {
+				names = append(names, w.Name)
+				byName[w.Name] = w
+			}
+
+			chosen := ""
+			if err := survey.AskOne(
+				&survey.Select{Message: "Default workspace:", Options: names},
+				&chosen,
+				survey.WithValidator(survey.Required),
+			); err != nil {
+				return err
+			}
+			deps.Viper.Set(WORKSPACE, byName[chosen].ID)
+
+			interactive := false
+			if err := survey.AskOne(
+				&survey.Confirm{Message: "Confirm/complement commands input before executing them (interactive mode)?"},
+				&interactive,
+			); err != nil {
+				return err
+			}
+			deps.Viper.Set(INTERACTIVE, interactive)
+
+			allowNameForID := false
+			if err := survey.AskOne(
+				&survey.Confirm{Message: "Allow using a project/tag's name wherever an id is asked?"},
+				&allowNameForID,
+			); err != nil {
+				return err
+			}
+			deps.Viper.Set(ALLOW_NAME_FOR_ID, allowNameForID)
+
+			if err := writeConfig(deps.Viper); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "config written to %s\n", deps.Viper.ConfigFileUsed())
+			return nil
+		},
+	}
+}
+
+// discoverUser calls GET /user to find the id behind the token just
+// entered, so the user doesn't have to look it up themselves.
+func discoverUser(c *clockify.Client) (user, error) {
+	req, err := c.NewRequest("GET", "user", nil)
+	if err != nil {
+		return user{}, err
+	}
+
+	var u user
+	if _, err := c.Do(req, &u); err != nil {
+		return user{}, err
+	}
+	return u, nil
+}
+
+// listWorkspaces calls GET /workspaces so the user can pick a default one
+// from a list instead of having to know its id upfront.
+func listWorkspaces(c *clockify.Client) ([]workspace, error) {
+	req, err := c.NewRequest("GET", "workspaces", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var ws []workspace
+	if _, err := c.Do(req, &ws); err != nil {
+		return nil, err
+	}
+	return ws, nil
+}
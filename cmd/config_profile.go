@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	clockify "github.com/lucassabreu/clockify-cli/http"
+	"github.com/lucassabreu/clockify-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// PROFILE is the persistent flag/viper key used to pick which profile a
+// command should run with.
+const PROFILE = "profile"
+
+// DEFAULT_PROFILE is the top-level config key naming the profile to use
+// when --profile/$CLOCKIFY_PROFILE isn't set.
+const DEFAULT_PROFILE = "default_profile"
+
+// PROFILES is the top-level config key holding the profiles map.
+const PROFILES = "profiles"
+
+// Profile groups the credentials and defaults for a single Clockify
+// workspace/account, so a single .clockify-cli.yaml can be shared across
+// several of them.
+type Profile struct {
+	Token          string `mapstructure:"token"`
+	Workspace      string `mapstructure:"workspace"`
+	UserID         string `mapstructure:"user-id"`
+	BaseURL        string `mapstructure:"base-url"`
+	AllowNameForID bool   `mapstructure:"allow-name-for-id"`
+}
+
+// ActiveProfileName resolves which profile a command should use: the
+// --profile flag/$CLOCKIFY_PROFILE env, falling back to default_profile.
+func ActiveProfileName(v *viper.Viper) string {
+	if name := v.GetString(PROFILE); name != "" {
+		return name
+	}
+	return v.GetString(DEFAULT_PROFILE)
+}
+
+// GetProfile looks up a named profile from v. The second return value is
+// false if no such profile exists.
+func GetProfile(v *viper.Viper, name string) (Profile, bool) {
+	if name == "" || !v.IsSet(PROFILES+"."+name) {
+		return Profile{}, false
+	}
+
+	var p Profile
+	if err := v.UnmarshalKey(PROFILES+"."+name, &p); err != nil {
+		return Profile{}, false
+	}
+	return p, true
+}
+
+// ActiveProfile looks up the profile returned by ActiveProfileName.
+func (a App) ActiveProfile() (name string, p Profile, ok bool) {
+	name = ActiveProfileName(a.Viper)
+	p, ok = GetProfile(a.Viper, name)
+	return name, p, ok
+}
+
+// applyActiveProfile overrides the WORKSPACE/USER_ID/ALLOW_NAME_FOR_ID config
+// keys with the active profile's values, the same way HTTPClient resolves
+// token/base-url from it. It's called once per command invocation (from
+// NewRootCmd's PersistentPreRunE) rather than read on demand, since unlike
+// HTTPClient these keys are read directly off Viper in several places.
+func applyActiveProfile(v *viper.Viper) {
+	p, ok := GetProfile(v, ActiveProfileName(v))
+	if !ok {
+		return
+	}
+
+	if p.Workspace != "" {
+		v.Set(WORKSPACE, p.Workspace)
+	}
+	if p.UserID != "" {
+		v.Set(USER_ID, p.UserID)
+	}
+	// AllowNameForID has no unset sentinel, so a profile can only opt in,
+	// never override a true flag/env value back to false.
+	if p.AllowNameForID {
+		v.Set(ALLOW_NAME_FOR_ID, true)
+	}
+}
+
+// HTTPClient builds a Clockify http.Client for the active profile, falling
+// back to the top-level --token/base-url config when there isn't one (or
+// it doesn't override a given field). The logger is built from the current
+// --log-format/--log-level flags on every call, since a.Viper is only
+// guaranteed to have them parsed once the command actually runs.
+func (a App) HTTPClient() (*clockify.Client, error) {
+	token := a.Viper.GetString(TOKEN)
+	baseURL := a.Viper.GetString("base-url")
+
+	if _, p, ok := a.ActiveProfile(); ok {
+		if p.Token != "" {
+			token = p.Token
+		}
+		if p.BaseURL != "" {
+			baseURL = p.BaseURL
+		}
+	}
+
+	logger, err := newLogger(a.Viper.GetString(LOG_FORMAT), a.Viper.GetString(LOG_LEVEL))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.NewHttpClient(baseURL, token, clockify.ClientOptions{
+		Logger:      logger,
+		RetryPolicy: retryPolicy(a.Viper),
+	})
+}
+
+// retryPolicy builds a clockify.RetryPolicy from the retry.* config keys
+// (see internal/config.SetDefaults for their defaults), so --config/env
+// overrides actually reach the HTTP client's retry behavior.
+func retryPolicy(v *viper.Viper) clockify.RetryPolicy {
+	return clockify.RetryPolicy{
+		MaxAttempts: v.GetInt("retry.max-attempts"),
+		BaseDelay:   v.GetDuration("retry.base-delay"),
+		MaxDelay:    v.GetDuration("retry.max-delay"),
+	}
+}
+
+// rawProfiles returns the profiles map as-is (name -> raw settings), the
+// same shape newConfigProfileAddCmd writes. Callers that need to rewrite
+// the whole profiles tree (e.g. on removal) must keep using this raw shape
+// instead of decoding into Profile structs: Profile has no yaml tags, so
+// re-serializing a map[string]Profile would rename every other profile's
+// fields to the struct's default (tag-less) field names and corrupt them
+// on the next read.
+func rawProfiles(v *viper.Viper) map[string]interface{} {
+	return v.GetStringMap(PROFILES)
+}
+
+// writeConfig persists v's settings to its config file, resolving one via
+// config.FilePath if none was set on startup, and creating the file's
+// directory if it doesn't exist yet.
+func writeConfig(v *viper.Viper) error {
+	path := v.ConfigFileUsed()
+	if path == "" {
+		p, err := config.FilePath()
+		if err != nil {
+			return err
+		}
+		path = p
+		v.SetConfigFile(path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return v.WriteConfigAs(path)
+}
+
+// NewConfigProfileCmd builds the `config profile` command group.
+func NewConfigProfileCmd(deps App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Short:   "Manage named profiles for different workspaces/accounts",
+		Aliases: []string{"profiles"},
+	}
+
+	cmd.AddCommand(newConfigProfileAddCmd(deps))
+	cmd.AddCommand(newConfigProfileListCmd(deps))
+	cmd.AddCommand(newConfigProfileRemoveCmd(deps))
+	cmd.AddCommand(newConfigProfileUseCmd(deps))
+	cmd.AddCommand(newConfigProfileShowCmd(deps))
+
+	return cmd
+}
+
+func newConfigProfileAddCmd(deps App) *cobra.Command {
+	var p Profile
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add (or replace) a named profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			deps.Viper.Set(PROFILES+"."+name, map[string]interface{}{
+				"token":             p.Token,
+				"workspace":         p.Workspace,
+				"user-id":           p.UserID,
+				"base-url":          p.BaseURL,
+				"allow-name-for-id": p.AllowNameForID,
+			})
+
+			if err := writeConfig(deps.Viper); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "profile %q saved\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&p.Token, "token", "", "clockify's token for this profile")
+	_ = cmd.MarkFlagRequired("token")
+	cmd.Flags().StringVar(&p.Workspace, "workspace", "", "default workspace for this profile")
+	cmd.Flags().StringVar(&p.UserID, "user-id", "", "default user id for this profile")
+	cmd.Flags().StringVar(&p.BaseURL, "base-url", "", "base URL to use for this profile (e.g. for a self-hosted instance)")
+	cmd.Flags().BoolVar(&p.AllowNameForID, "allow-name-for-id", false, "allow use of project/tag's name when id is asked")
+
+	return cmd
+}
+
+func newConfigProfileListCmd(deps App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Short:   "List the configured profiles",
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profiles := rawProfiles(deps.Viper)
+
+			names := make([]string, 0, len(profiles))
+			for name := range profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			active := ActiveProfileName(deps.Viper)
+			for _, name := range names {
+				mark := "  "
+				if name == active {
+					mark = "* "
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s%s\n", mark, name)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newConfigProfileRemoveCmd(deps App) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove a named profile",
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			profiles := rawProfiles(deps.Viper)
+			if _, ok := profiles[name]; !ok {
+				return fmt.Errorf("profile %q not found", name)
+			}
+			delete(profiles, name)
+
+			deps.Viper.Set(PROFILES, profiles)
+			if err := writeConfig(deps.Viper); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "profile %q removed\n", name)
+			return nil
+		},
+	}
+}
+
+func newConfigProfileUseCmd(deps App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the default profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			if _, ok := GetProfile(deps.Viper, name); !ok {
+				return fmt.Errorf("profile %q not found", name)
+			}
+
+			deps.Viper.Set(DEFAULT_PROFILE, name)
+			if err := writeConfig(deps.Viper); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "default profile set to %q\n", name)
+			return nil
+		},
+	}
+}
+
+func newConfigProfileShowCmd(deps App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show [name]",
+		Short: "Show a profile's settings (defaults to the active profile)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := ActiveProfileName(deps.Viper)
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			p, ok := GetProfile(deps.Viper, name)
+			if !ok {
+				return fmt.Errorf("profile %q not found", name)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "name: %s\n", name)
+			fmt.Fprintf(out, "workspace: %s\n", p.Workspace)
+			fmt.Fprintf(out, "user-id: %s\n", p.UserID)
+			fmt.Fprintf(out, "base-url: %s\n", p.BaseURL)
+			fmt.Fprintf(out, "allow-name-for-id: %t\n", p.AllowNameForID)
+			fmt.Fprintf(out, "token: %s\n", redactToken(p.Token))
+
+			return nil
+		},
+	}
+}
+
+// redactToken keeps a profile's token out of plain-text output, while
+// still showing enough of it for the user to tell profiles apart.
+func redactToken(token string) string {
+	if len(token) <= 4 {
+		return "****"
+	}
+	return "****" + token[len(token)-4:]
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	clockify "github.com/lucassabreu/clockify-cli/http"
+	"github.com/spf13/viper"
+)
+
+func newTestViper(t *testing.T, configPath string) *viper.Viper {
+	t.Helper()
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+	return v
+}
+
+func TestGetProfile(t *testing.T) {
+	v := viper.New()
+	v.Set(PROFILES+".work", map[string]interface{}{
+		"token":     "tok",
+		"workspace": "ws-1",
+	})
+
+	tests := []struct {
+		name    string
+		profile string
+		wantOk  bool
+	}{
+		{"existing profile", "work", true},
+		{"missing profile", "personal", false},
+		{"empty name", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := GetProfile(v, tt.profile)
+			if ok != tt.wantOk {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOk)
+			}
+			if tt.wantOk && p.Token != "tok" {
+				t.Errorf("Token = %q, want %q", p.Token, "tok")
+			}
+		})
+	}
+}
+
+func TestActiveProfileName(t *testing.T) {
+	t.Run("falls back to default_profile when --profile is unset", func(t *testing.T) {
+		v := viper.New()
+		v.Set(DEFAULT_PROFILE, "work")
+
+		if got := ActiveProfileName(v); got != "work" {
+			t.Errorf("got %q, want %q", got, "work")
+		}
+	})
+
+	t.Run("prefers profile over default_profile", func(t *testing.T) {
+		v := viper.New()
+		v.Set(PROFILE, "personal")
+		v.Set(DEFAULT_PROFILE, "work")
+
+		if got := ActiveProfileName(v); got != "personal" {
+			t.Errorf("got %q, want %q", got, "personal")
+		}
+	})
+}
+
+func TestHTTPClientPrecedence(t *testing.T) {
+	var gotBaseURL, gotToken string
+
+	newApp := func(v *viper.Viper) App {
+		v.SetDefault(LOG_FORMAT, "text")
+		v.SetDefault(LOG_LEVEL, "info")
+		return App{
+			Viper: v,
+			NewHttpClient: func(baseURL, apiKey string, opts clockify.ClientOptions) (*clockify.Client, error) {
+				gotBaseURL, gotToken = baseURL, apiKey
+				return clockify.NewHttpClientWithOptions(baseURL, apiKey, opts)
+			},
+		}
+	}
+
+	t.Run("uses top-level token/base-url when no profile is active", func(t *testing.T) {
+		v := viper.New()
+		v.Set(TOKEN, "top-level-token")
+		v.Set("base-url", "https://top-level.example.com")
+
+		if _, err := newApp(v).HTTPClient(); err != nil {
+			t.Fatal(err)
+		}
+		if gotToken != "top-level-token" || gotBaseURL != "https://top-level.example.com" {
+			t.Errorf("got token=%q base-url=%q", gotToken, gotBaseURL)
+		}
+	})
+
+	t.Run("active profile overrides top-level token/base-url", func(t *testing.T) {
+		v := viper.New()
+		v.Set(TOKEN, "top-level-token")
+		v.Set("base-url", "https://top-level.example.com")
+		v.Set(PROFILE, "work")
+		v.Set(PROFILES+".work", map[string]interface{}{
+			"token":    "profile-token",
+			"base-url": "https://work.example.com",
+		})
+
+		if _, err := newApp(v).HTTPClient(); err != nil {
+			t.Fatal(err)
+		}
+		if gotToken != "profile-token" || gotBaseURL != "https://work.example.com" {
+			t.Errorf("got token=%q base-url=%q", gotToken, gotBaseURL)
+		}
+	})
+
+	t.Run("profile doesn't override an unset field", func(t *testing.T) {
+		v := viper.New()
+		v.Set(TOKEN, "top-level-token")
+		v.Set("base-url", "https://top-level.example.com")
+		v.Set(PROFILE, "work")
+		v.Set(PROFILES+".work", map[string]interface{}{
+			"token": "profile-token",
+		})
+
+		if _, err := newApp(v).HTTPClient(); err != nil {
+			t.Fatal(err)
+		}
+		if gotToken != "profile-token" || gotBaseURL != "https://top-level.example.com" {
+			t.Errorf("got token=%q base-url=%q", gotToken, gotBaseURL)
+		}
+	})
+}
+
+func TestApplyActiveProfile(t *testing.T) {
+	t.Run("overrides workspace and user-id", func(t *testing.T) {
+		v := viper.New()
+		v.Set(PROFILE, "work")
+		v.Set(PROFILES+".work", map[string]interface{}{
+			"workspace": "ws-work",
+			"user-id":   "user-work",
+		})
+
+		applyActiveProfile(v)
+
+		if got := v.GetString(WORKSPACE); got != "ws-work" {
+			t.Errorf("workspace = %q, want %q", got, "ws-work")
+		}
+		if got := v.GetString(USER_ID); got != "user-work" {
+			t.Errorf("user-id = %q, want %q", got, "user-work")
+		}
+	})
+
+	t.Run("allow-name-for-id can only be turned on, never off", func(t *testing.T) {
+		v := viper.New()
+		v.Set(ALLOW_NAME_FOR_ID, true)
+		v.Set(PROFILE, "work")
+		v.Set(PROFILES+".work", map[string]interface{}{})
+
+		applyActiveProfile(v)
+
+		if !v.GetBool(ALLOW_NAME_FOR_ID) {
+			t.Error("expected allow-name-for-id to remain true")
+		}
+	})
+
+	t.Run("no-op without an active profile", func(t *testing.T) {
+		v := viper.New()
+		v.Set(WORKSPACE, "ws-flag")
+
+		applyActiveProfile(v)
+
+		if got := v.GetString(WORKSPACE); got != "ws-flag" {
+			t.Errorf("workspace = %q, want unchanged %q", got, "ws-flag")
+		}
+	})
+}
+
+// TestConfigProfileAddRemoveRoundTrip writes and removes profiles against a
+// real temp config file, the way a user would via `config profile add`/
+// `remove`, guarding against the map[string]Profile re-serialization bug
+// fixed for chunk0-4 (it corrupted every other profile's fields on removal).
+func TestConfigProfileAddRemoveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	v := newTestViper(t, path)
+	deps := App{Viper: v, Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+
+	runAdd := func(name string, args ...string) {
+		t.Helper()
+		cmd := newConfigProfileAddCmd(deps)
+		cmd.SetArgs(append([]string{name}, args...))
+		cmd.SetOut(deps.Out)
+		cmd.SetErr(deps.Err)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("add %s: %v", name, err)
+		}
+	}
+
+	runAdd("work", "--token", "work-token", "--workspace", "ws-work",
+		"--user-id", "user-work", "--base-url", "https://work.example.com")
+	runAdd("personal", "--token", "personal-token", "--workspace", "ws-personal",
+		"--user-id", "user-personal", "--allow-name-for-id")
+
+	// Reload from disk the way a fresh command invocation would, instead of
+	// relying on the in-memory viper state the add commands just wrote.
+	v2 := newTestViper(t, path)
+	if err := v2.ReadInConfig(); err != nil {
+		t.Fatalf("read back config: %v", err)
+	}
+
+	removeDeps := App{Viper: v2, Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	removeCmd := newConfigProfileRemoveCmd(removeDeps)
+	removeCmd.SetArgs([]string{"work"})
+	removeCmd.SetOut(removeDeps.Out)
+	removeCmd.SetErr(removeDeps.Err)
+	if err := removeCmd.Execute(); err != nil {
+		t.Fatalf("remove work: %v", err)
+	}
+
+	v3 := newTestViper(t, path)
+	if err := v3.ReadInConfig(); err != nil {
+		t.Fatalf("read back config after removal: %v", err)
+	}
+
+	if _, ok := GetProfile(v3, "work"); ok {
+		t.Error("expected profile \"work\" to have been removed")
+	}
+
+	personal, ok := GetProfile(v3, "personal")
+	if !ok {
+		t.Fatal("expected profile \"personal\" to still exist")
+	}
+	if personal.Token != "personal-token" {
+		t.Errorf("Token = %q, want %q", personal.Token, "personal-token")
+	}
+	if personal.Workspace != "ws-personal" {
+		t.Errorf("Workspace = %q, want %q", personal.Workspace, "ws-personal")
+	}
+	if personal.UserID != "user-personal" {
+		t.Errorf("UserID = %q, want %q", personal.UserID, "user-personal")
+	}
+	if !personal.AllowNameForID {
+		t.Error("AllowNameForID was corrupted by the removal of another profile")
+	}
+}
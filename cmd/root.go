@@ -16,94 +16,126 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/lucassabreu/clockify-cli/cmd/completion"
-	homedir "github.com/mitchellh/go-homedir"
+	clockify "github.com/lucassabreu/clockify-cli/http"
+	"github.com/lucassabreu/clockify-cli/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var (
-	cfgFile string
-
-	version   string
-	commit    string
-	buildDate string
-)
+const USER_ID_FLAG = "user-id"
+const ENV_PREFIX = "CLOCKIFY"
 
-// rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:           "clockify-cli",
-	Short:         "Allow to integrate with Clockify through terminal",
-	SilenceErrors: true,
-	SilenceUsage:  true,
+const LOG_FORMAT = "log-format"
+const LOG_LEVEL = "log-level"
+
+// App carries the dependencies every subcommand needs. It replaces the
+// package-level rootCmd/cfgFile/version/viper globals this CLI used to rely
+// on, so commands can be built and tested in isolation (e.g. with a fake
+// Clockify transport) instead of sharing process-global state.
+type App struct {
+	// Viper holds the resolved configuration (flags, env vars, config
+	// file). Each App gets its own instance instead of binding to the
+	// package-level viper.GetViper().
+	Viper *viper.Viper
+
+	// NewHttpClient builds a Clockify http.Client for a given base URL,
+	// token and options (logger, retry policy). Subcommands call it
+	// through App.HTTPClient instead of http.NewHttpClientWithOptions
+	// directly, so tests can inject a client pointed at a fake transport.
+	NewHttpClient func(baseURL, apiKey string, opts clockify.ClientOptions) (*clockify.Client, error)
+
+	Out, Err io.Writer
+
+	Version   string
+	Commit    string
+	BuildDate string
 }
 
-// Execute adds all child commands to the root command and sets flags appropriately.
-// This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute(v, c, d string) {
-	version = v
-	commit = c
-	buildDate = d
+// NewRootCmd builds the base command when called without any subcommands.
+// Subcommand packages register themselves on the returned command through
+// their own NewXCmd(deps App) *cobra.Command constructor.
+func NewRootCmd(deps App) *cobra.Command {
+	if deps.Out == nil {
+		deps.Out = os.Stdout
+	}
+	if deps.Err == nil {
+		deps.Err = os.Stderr
+	}
 
-	if err := rootCmd.Execute(); err != nil {
-		if viper.GetBool("debug") {
-			fmt.Fprintf(os.Stderr, "%+v\n", err)
-		} else {
-			fmt.Fprintln(os.Stderr, err.Error())
-		}
-		os.Exit(1)
+	var cfgFile string
+
+	rootCmd := &cobra.Command{
+		Use:           "clockify-cli",
+		Short:         "Allow to integrate with Clockify through terminal",
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
-}
 
-const USER_ID_FLAG = "user-id"
-const ENV_PREFIX = "CLOCKIFY"
+	rootCmd.SetOut(deps.Out)
+	rootCmd.SetErr(deps.Err)
 
-func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(func() { initConfig(deps.Viper, cfgFile) })
 
-	viper.SetEnvPrefix(ENV_PREFIX)
+	deps.Viper.SetEnvPrefix(ENV_PREFIX)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.clockify-cli.yaml)")
 
+	rootCmd.PersistentFlags().StringP(PROFILE, "P", "",
+		"named profile to use, from the config file's `profiles` map (defaults to env $"+ENV_PREFIX+"_PROFILE, then the `default_profile` config key)",
+	)
+	_ = deps.Viper.BindPFlag(PROFILE, rootCmd.PersistentFlags().Lookup(PROFILE))
+
 	rootCmd.PersistentFlags().StringP(TOKEN, "t", "",
 		"clockify's token (defaults to env $"+ENV_PREFIX+"_TOKEN)\n"+
 			"\tCan be generated here: https://clockify.me/user/settings#generateApiKeyBtn",
 	)
-	_ = viper.BindPFlag(TOKEN, rootCmd.PersistentFlags().Lookup(TOKEN))
+	_ = deps.Viper.BindPFlag(TOKEN, rootCmd.PersistentFlags().Lookup(TOKEN))
 
 	rootCmd.PersistentFlags().StringP(WORKSPACE, "w", "", "workspace to be used (defaults to env $"+ENV_PREFIX+"_WORKSPACE)")
-	_ = viper.BindPFlag(WORKSPACE, rootCmd.PersistentFlags().Lookup(WORKSPACE))
-	_ = completion.AddSuggestionsToFlag(rootCmd, WORKSPACE, suggestWithClientAPI(suggestWorkspaces))
+	_ = deps.Viper.BindPFlag(WORKSPACE, rootCmd.PersistentFlags().Lookup(WORKSPACE))
+	_ = completion.AddSuggestionsToFlag(rootCmd, WORKSPACE, suggestWithClientAPI(deps, suggestWorkspaces))
 
 	rootCmd.PersistentFlags().StringP(USER_ID_FLAG, "u", "", "user id from the token (defaults to env $"+ENV_PREFIX+"_USER_ID)")
-	_ = viper.BindPFlag(USER_ID, rootCmd.PersistentFlags().Lookup(USER_ID_FLAG))
-	_ = completion.AddSuggestionsToFlag(rootCmd, USER_ID, suggestWithClientAPI(suggestUsers))
+	_ = deps.Viper.BindPFlag(USER_ID, rootCmd.PersistentFlags().Lookup(USER_ID_FLAG))
+	_ = completion.AddSuggestionsToFlag(rootCmd, USER_ID, suggestWithClientAPI(deps, suggestUsers))
 
 	rootCmd.PersistentFlags().Bool("debug", false, "show debug log (defaults to env $"+ENV_PREFIX+"_DEBUG)")
-	_ = viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	_ = deps.Viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+
+	rootCmd.PersistentFlags().String(LOG_FORMAT, "text", "log output format, one of: json, text (defaults to env $"+ENV_PREFIX+"_LOG_FORMAT)")
+	_ = deps.Viper.BindPFlag(LOG_FORMAT, rootCmd.PersistentFlags().Lookup(LOG_FORMAT))
+	_ = deps.Viper.BindEnv(LOG_FORMAT, ENV_PREFIX+"_LOG_FORMAT")
+
+	rootCmd.PersistentFlags().String(LOG_LEVEL, "info", "minimum log level, one of: debug, info, warn, error (defaults to env $"+ENV_PREFIX+"_LOG_LEVEL)")
+	_ = deps.Viper.BindPFlag(LOG_LEVEL, rootCmd.PersistentFlags().Lookup(LOG_LEVEL))
+	_ = deps.Viper.BindEnv(LOG_LEVEL, ENV_PREFIX+"_LOG_LEVEL")
 
 	rootCmd.PersistentFlags().BoolP(INTERACTIVE, "i", false,
 		"will prompt you to confirm/complement commands input before executing the action (defaults to env $"+ENV_PREFIX+"_INTERACTIVE).\n"+
 			"\tYou can be disable it temporally by setting it to 0 (-i=0 or "+ENV_PREFIX+"_INTERACTIVE=0)",
 	)
-	_ = viper.BindPFlag(INTERACTIVE, rootCmd.PersistentFlags().Lookup(INTERACTIVE))
+	_ = deps.Viper.BindPFlag(INTERACTIVE, rootCmd.PersistentFlags().Lookup(INTERACTIVE))
 
-	viper.RegisterAlias(ALLOW_NAME_FOR_ID, "allow-project-name")
+	deps.Viper.RegisterAlias(ALLOW_NAME_FOR_ID, "allow-project-name")
 
 	rootCmd.PersistentFlags().BoolP("allow-project-name", "", false, "allow use of project name when id is asked (defaults to env $"+ENV_PREFIX+"_ALLOW_PROJECT_NAME)")
 	_ = rootCmd.Flags().MarkDeprecated("allow-project-name", "use `allow-name-for-id` flag instead")
-	_ = viper.BindPFlag("allow-project-name", rootCmd.PersistentFlags().Lookup("allow-project-name"))
+	_ = deps.Viper.BindPFlag("allow-project-name", rootCmd.PersistentFlags().Lookup("allow-project-name"))
 
 	rootCmd.PersistentFlags().BoolP(ALLOW_NAME_FOR_ID, "", false, "allow use of project/tag's name when id is asked (defaults to env $"+ENV_PREFIX+"_ALLOW_NAME_FOR_ID)")
-	_ = viper.BindPFlag(ALLOW_NAME_FOR_ID, rootCmd.PersistentFlags().Lookup(ALLOW_NAME_FOR_ID))
+	_ = deps.Viper.BindPFlag(ALLOW_NAME_FOR_ID, rootCmd.PersistentFlags().Lookup(ALLOW_NAME_FOR_ID))
 
-	_ = viper.BindEnv(
+	_ = deps.Viper.BindEnv(
 		ALLOW_NAME_FOR_ID,
 		ENV_PREFIX+"_ALLOW_NAME_FOR_ID",
 		ENV_PREFIX+"_ALLOW_PROJECT_NAME",
@@ -116,33 +148,95 @@ func init() {
 		cmd.Println(cmd.UsageString())
 		return nil
 	})
+
+	// Apply the active profile's workspace/user-id/allow-name-for-id once
+	// flags are parsed. This only mutates deps.Viper (a pointer shared by
+	// every subcommand already registered below), never the App value
+	// itself, so it's visible everywhere -- unlike the token/base-url
+	// overrides HTTPClient resolves per call, these keys are read directly
+	// off Viper in several places, so they need to land on it up front.
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		applyActiveProfile(deps.Viper)
+		return nil
+	}
+
+	rootCmd.AddCommand(NewConfigCmd(deps))
+	rootCmd.AddCommand(NewSupportCmd(deps))
+
+	return rootCmd
+}
+
+// Execute builds the root command from deps and runs it.
+// This is called by main.main(). It only needs to happen once.
+func Execute(deps App) {
+	rootCmd := NewRootCmd(deps)
+
+	if err := rootCmd.Execute(); err != nil {
+		if deps.Viper.GetBool("debug") {
+			fmt.Fprintf(deps.Err, "%+v\n", err)
+		} else {
+			fmt.Fprintln(deps.Err, err.Error())
+		}
+		os.Exit(1)
+	}
+}
+
+// newLogger builds the *zap.Logger used by every Client created through
+// App.NewHttpClient, from the --log-format/--log-level flags.
+func newLogger(format, level string) (*zap.Logger, error) {
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", LOG_LEVEL, level, err)
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+	cfg.EncoderConfig.TimeKey = "timestamp"
+	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	switch format {
+	case "json":
+		cfg.Encoding = "json"
+	case "text":
+		cfg.Encoding = "console"
+		cfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	default:
+		return nil, fmt.Errorf("invalid %s %q: must be json or text", LOG_FORMAT, format)
+	}
+
+	return cfg.Build()
 }
 
 // initConfig reads in config file and ENV variables if set.
-func initConfig() {
+func initConfig(v *viper.Viper, cfgFile string) {
+	config.SetDefaults(v)
+
 	if cfgFile != "" {
 		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
+		v.SetConfigFile(cfgFile)
 	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
+		// Search in $XDG_CONFIG_HOME/clockify-cli, ~/.config/clockify-cli,
+		// then the legacy ~/.clockify-cli.yaml, migrating the latter if
+		// that's the only one found.
+		path, err := config.FilePath()
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-
-		// Search config in home directory with name ".clockify-cli" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".clockify-cli")
+		v.SetConfigFile(path)
 	}
 
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	viper.SetEnvPrefix("clockify")
-	viper.AutomaticEnv() // read in environment variables that match
+	v.SetEnvPrefix("clockify")
+	v.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+
 		switch err.(type) {
 		case viper.ConfigFileNotFoundError:
 			return
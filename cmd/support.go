@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewSupportCmd builds the `support` command group, used to help users put
+// together bug reports.
+func NewSupportCmd(deps App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "support",
+		Short: "Tools to help diagnose and report issues with clockify-cli",
+	}
+
+	cmd.AddCommand(NewSupportDumpCmd(deps))
+
+	return cmd
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lucassabreu/clockify-cli/internal/diagnostics"
+	"github.com/spf13/cobra"
+)
+
+// buildInfo mirrors the version/commit/build-date metadata set on App.
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// pingResult is the outcome of probing the Clockify API's base URL.
+type pingResult struct {
+	URL       string `json:"url"`
+	Status    int    `json:"status,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// supportBundle is the diagnostics bundle collected by `support dump`.
+type supportBundle struct {
+	Build      buildInfo              `json:"build"`
+	OS         string                 `json:"os"`
+	Arch       string                 `json:"arch"`
+	GoVersion  string                 `json:"go_version"`
+	Config     map[string]interface{} `json:"config"`
+	Ping       pingResult             `json:"ping"`
+	User       *user                  `json:"user,omitempty"`
+	Workspaces []workspace            `json:"workspaces,omitempty"`
+	Logs       []string               `json:"logs"`
+}
+
+// NewSupportDumpCmd builds the `support dump` command.
+func NewSupportDumpCmd(deps App) *cobra.Command {
+	var toStdout bool
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Collect a redacted diagnostics bundle for bug reports",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logs := diagnostics.NewRingBuffer(200)
+
+			bundle := supportBundle{
+				Build: buildInfo{
+					Version:   deps.Version,
+					Commit:    deps.Commit,
+					BuildDate: deps.BuildDate,
+				},
+				OS:        runtime.GOOS,
+				Arch:      runtime.GOARCH,
+				GoVersion: runtime.Version(),
+				Config:    redactSettings(deps.Viper.AllSettings()),
+				Ping:      pingBaseURL(deps.Viper.GetString("base-url")),
+			}
+
+			if c, err := deps.HTTPClient(); err == nil {
+				c.SetLogSink(logs)
+
+				if u, err := discoverUser(c); err == nil {
+					bundle.User = &u
+				}
+				if ws, err := listWorkspaces(c); err == nil {
+					bundle.Workspaces = ws
+				}
+			}
+			bundle.Logs = logs.Lines()
+
+			b, err := json.MarshalIndent(bundle, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			if toStdout {
+				fmt.Fprintln(cmd.OutOrStdout(), string(b))
+				return nil
+			}
+
+			if err := writeSupportTarball(output, b); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "diagnostics bundle written to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&toStdout, "stdout", false, "print the bundle as JSON to stdout instead of writing a tarball")
+	cmd.Flags().StringVar(&output, "output", "clockify-cli-support.tar.gz", "path to write the diagnostics tarball to")
+
+	return cmd
+}
+
+// pingBaseURL probes baseURL and records its status and round-trip latency.
+func pingBaseURL(baseURL string) pingResult {
+	result := pingResult{URL: baseURL}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+	resp, err := client.Get(baseURL)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.Status = resp.StatusCode
+	return result
+}
+
+// redactSettings walks v's resolved viper settings, masking any key that
+// looks like a credential, so the bundle can be shared safely.
+func redactSettings(v map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(v))
+	for k, val := range v {
+		out[k] = redactSettingsValue(k, val)
+	}
+	return out
+}
+
+func redactSettingsValue(key string, val interface{}) interface{} {
+	switch t := val.(type) {
+	case map[string]interface{}:
+		return redactSettings(t)
+	case string:
+		if strings.Contains(strings.ToLower(key), "token") {
+			return redactToken(t)
+		}
+		return t
+	default:
+		return val
+	}
+}
+
+// writeSupportTarball writes bundleJSON as diagnostics.json inside a
+// gzip-compressed tarball at path. tar.Writer and gzip.Writer both buffer
+// and flush trailing data on Close, so a failure there means a truncated
+// tarball -- their errors are checked explicitly rather than left to defer.
+func writeSupportTarball(path string, bundleJSON []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "diagnostics.json",
+		Mode: 0o600,
+		Size: int64(len(bundleJSON)),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(bundleJSON); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
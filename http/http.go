@@ -2,15 +2,23 @@ package http
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	mathrand "math/rand"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	stackedErrors "github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // ErrorMissingAPIKey returned if X-Api-Key is missing
@@ -19,7 +27,35 @@ var ErrorMissingAPIKey = errors.New("api Key must be informed")
 type Client struct {
 	baseURL url.URL
 	http.Client
-	Logger Logger
+	// Logger receives structured request/response traces. Defaults to a
+	// no-op logger, so it is always safe to log against.
+	Logger      *zap.Logger
+	retryPolicy RetryPolicy
+	logSink     io.Writer
+}
+
+// SetLogSink makes every request/response trace also be written as JSON to
+// w, in addition to Logger. This lets callers (like `support dump`) capture
+// a recent request trace without needing a global logger.
+func (c *Client) SetLogSink(w io.Writer) {
+	c.logSink = w
+}
+
+// logger returns c.Logger, teeing it into logSink at debug level when one
+// is set.
+func (c *Client) logger() *zap.Logger {
+	if c.logSink == nil {
+		return c.Logger
+	}
+
+	sink := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(c.logSink),
+		zapcore.DebugLevel,
+	)
+	return c.Logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, sink)
+	}))
 }
 
 type transport struct {
@@ -33,8 +69,49 @@ func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return t.next.RoundTrip(r)
 }
 
+// RetryPolicy describes how Do should retry a request that failed with a
+// transient error (429/502/503/504 or a network error).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times a request will be tried,
+	// including the first one. A value <= 1 disables retrying.
+	MaxAttempts int
+	// BaseDelay is the upper bound of the first retry's delay, doubled on
+	// every attempt after that (exponential backoff); the actual delay is a
+	// random value between 0 and that bound (full jitter).
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff, regardless of attempt count or
+	// a server provided Retry-After.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is used by NewHttpClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// ClientOptions customizes the Client built by NewHttpClientWithOptions.
+type ClientOptions struct {
+	RetryPolicy RetryPolicy
+	// Logger receives structured request/response traces. A nil Logger
+	// falls back to zap.NewNop().
+	Logger *zap.Logger
+}
+
+// DefaultClientOptions is used by NewHttpClient.
+var DefaultClientOptions = ClientOptions{
+	RetryPolicy: DefaultRetryPolicy,
+}
+
 // NewHttpClient create a new Client, based on: https://clockify.me/developers-api
 func NewHttpClient(baseURL, apiKey string) (*Client, error) {
+	return NewHttpClientWithOptions(baseURL, apiKey, DefaultClientOptions)
+}
+
+// NewHttpClientWithOptions creates a new Client, like NewHttpClient, but
+// lets the caller customize the retry policy and logger used.
+func NewHttpClientWithOptions(baseURL, apiKey string, opts ClientOptions) (*Client, error) {
 	if len(apiKey) == 0 {
 		return nil, stackedErrors.WithStack(ErrorMissingAPIKey)
 	}
@@ -44,6 +121,11 @@ func NewHttpClient(baseURL, apiKey string) (*Client, error) {
 		return nil, stackedErrors.WithStack(err)
 	}
 
+	logger := opts.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
 	c := &Client{
 		baseURL: *u,
 		Client: http.Client{
@@ -52,21 +134,46 @@ func NewHttpClient(baseURL, apiKey string) (*Client, error) {
 				next:   http.DefaultTransport,
 			},
 		},
+		retryPolicy: opts.RetryPolicy,
+		Logger:      logger,
 	}
 
 	return c, nil
 }
 
-type Logger interface {
-	Printf(string, ...interface{})
+// redactedHeader is set on any header that should never be logged in full.
+const redactedHeader = "X-Api-Key"
+
+// redactHeaders returns a copy of h with sensitive headers masked, suitable
+// for logging or dumping.
+func redactHeaders(h http.Header) http.Header {
+	c := h.Clone()
+	if c.Get(redactedHeader) != "" {
+		c.Set(redactedHeader, "***")
+	}
+	return c
 }
 
-func (c *Client) logf(format string, v ...interface{}) {
-	if c.Logger == nil {
-		return
+// newRequestID returns a short random hex id used to correlate the log
+// lines of a single request/retry sequence.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
 	}
+	return fmt.Sprintf("%x", b)
+}
+
+var workspacePathRE = regexp.MustCompile(`/workspaces/([^/]+)`)
 
-	c.Logger.Printf(format, v)
+// workspaceID best-effort extracts the workspace id from a Clockify API
+// path such as /workspaces/{id}/... for logging purposes.
+func workspaceID(u *url.URL) string {
+	m := workspacePathRE.FindStringSubmatch(u.Path)
+	if m == nil {
+		return ""
+	}
+	return m[1]
 }
 
 // QueryAppender an interface to identify if the parameters should be sent through the query or body
@@ -87,6 +194,85 @@ func (e Error) Error() string {
 // ErrorNotFound Not Found
 var ErrorNotFound = Error{Message: "Nothing was found"}
 
+type allowRetryKey struct{}
+
+// WithRetry marks a context so that a POST/PATCH request done with it is
+// allowed to be retried by Do. GET/HEAD/PUT/DELETE are always retryable, as
+// they are idempotent by definition; POST/PATCH need to opt-in through this
+// context, since retrying them blindly could duplicate side-effects.
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+func isRetryableMethod(ctx context.Context, method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost, http.MethodPatch:
+		allow, _ := ctx.Value(allowRetryKey{}).(bool)
+		return allow
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the Retry-After header, which the Clockify API may send
+// with a 429, in either its seconds or HTTP-date form.
+func retryAfter(r *http.Response) (time.Duration, bool) {
+	if r == nil {
+		return 0, false
+	}
+
+	h := r.Header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(h); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// backoff computes a jittered exponential delay for the given attempt
+// (1-indexed), honoring a server provided Retry-After when present.
+func (c *Client) backoff(r *http.Response, attempt int) time.Duration {
+	if d, ok := retryAfter(r); ok {
+		if d > c.retryPolicy.MaxDelay {
+			return c.retryPolicy.MaxDelay
+		}
+		return d
+	}
+
+	d := c.retryPolicy.BaseDelay << (attempt - 1)
+	if d <= 0 || d > c.retryPolicy.MaxDelay {
+		d = c.retryPolicy.MaxDelay
+	}
+
+	// full jitter: a random delay between 0 and d
+	return time.Duration(mathrand.Int63n(int64(d) + 1))
+}
+
 // NewRequest to be used in Client
 func (c *Client) NewRequest(method, uri string, body interface{}) (*http.Request, error) {
 	u, err := c.baseURL.Parse(strings.Join([]string{c.baseURL.Path, uri}, "/"))
@@ -109,7 +295,7 @@ func (c *Client) NewRequest(method, uri string, body interface{}) (*http.Request
 		if err != nil {
 			return nil, err
 		}
-		c.logf("request body: %s", buf.(*bytes.Buffer))
+		c.logger().Debug("request body", zap.String("url", u.String()), zap.Stringer("body", buf.(*bytes.Buffer)))
 	}
 
 	req, err := http.NewRequest(method, u.String(), buf)
@@ -125,10 +311,64 @@ func (c *Client) NewRequest(method, uri string, body interface{}) (*http.Request
 	return req, nil
 }
 
-// Do executes a http.Request inside the Clockify's Client
+// Do executes a http.Request inside the Clockify's Client, retrying
+// transient failures (429/502/503/504 or a network error) according to the
+// Client's retry policy before giving up.
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	r, err := c.Client.Do(req)
+	start := time.Now()
+	ctx := req.Context()
+	retryable := isRetryableMethod(ctx, req.Method)
+
+	logger := c.logger().With(
+		zap.String("request_id", newRequestID()),
+		zap.String("method", req.Method),
+		zap.String("url", req.URL.String()),
+		zap.String("workspace_id", workspaceID(req.URL)),
+	)
+	logger.Debug("request start", zap.Any("headers", redactHeaders(req.Header)))
+
+	maxAttempts := c.retryPolicy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var r *http.Response
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		r, err = c.Client.Do(req)
+
+		willRetry := retryable && attempt < maxAttempts &&
+			(err != nil || isRetryableStatus(r.StatusCode))
+		if !willRetry {
+			break
+		}
+
+		delay := c.backoff(r, attempt)
+		fields := []zap.Field{
+			zap.Int("retry_attempt", attempt),
+			zap.Duration("delay", delay),
+		}
+		if err != nil {
+			logger.Warn("retrying request", append(fields, zap.Error(err))...)
+		} else {
+			logger.Warn("retrying request", append(fields, zap.Int("status", r.StatusCode))...)
+			r.Body.Close()
+		}
+
+		if req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return r, stackedErrors.WithStack(berr)
+			}
+			req.Body = body
+		}
+
+		time.Sleep(delay)
+	}
+
+	elapsed := time.Since(start)
 	if err != nil {
+		logger.Error("request failed", zap.Error(err), zap.Int64("elapsed_ms", elapsed.Milliseconds()))
 		return r, err
 	}
 	defer r.Body.Close()
@@ -140,7 +380,11 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 		return nil, stackedErrors.WithStack(err)
 	}
 
-	c.logf("url: %s, status: %d, body: \"%s\"", req.URL.String(), r.StatusCode, buf)
+	logger.Debug("request end",
+		zap.Int("status", r.StatusCode),
+		zap.Int64("elapsed_ms", elapsed.Milliseconds()),
+		zap.String("body", buf.String()),
+	)
 
 	if r.StatusCode == 404 {
 		return r, stackedErrors.WithStack(ErrorNotFound)
@@ -154,6 +398,11 @@ func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
 		if err != nil {
 			return r, stackedErrors.WithStack(err)
 		}
+		logger.Warn("api error",
+			zap.Int("status", r.StatusCode),
+			zap.Int("code", apiErr.Code),
+			zap.Int64("elapsed_ms", elapsed.Milliseconds()),
+		)
 		return r, stackedErrors.WithStack(apiErr)
 	}
 
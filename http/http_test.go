@@ -0,0 +1,162 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		want   bool
+	}{
+		{"GET is always retryable", context.Background(), http.MethodGet, true},
+		{"HEAD is always retryable", context.Background(), http.MethodHead, true},
+		{"PUT is always retryable", context.Background(), http.MethodPut, true},
+		{"DELETE is always retryable", context.Background(), http.MethodDelete, true},
+		{"POST without opt-in is not retryable", context.Background(), http.MethodPost, false},
+		{"POST with opt-in is retryable", WithRetry(context.Background()), http.MethodPost, true},
+		{"PATCH without opt-in is not retryable", context.Background(), http.MethodPatch, false},
+		{"PATCH with opt-in is retryable", WithRetry(context.Background()), http.MethodPatch, true},
+		{"unknown method is not retryable", context.Background(), "TRACE", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableMethod(tt.ctx, tt.method); got != tt.want {
+				t.Errorf("isRetryableMethod(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.status); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("nil response", func(t *testing.T) {
+		if _, ok := retryAfter(nil); ok {
+			t.Error("expected ok=false for a nil response")
+		}
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		r := &http.Response{Header: http.Header{}}
+		if _, ok := retryAfter(r); ok {
+			t.Error("expected ok=false when Retry-After is absent")
+		}
+	})
+
+	t.Run("seconds form", func(t *testing.T) {
+		r := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+		d, ok := retryAfter(r)
+		if !ok {
+			t.Fatal("expected ok=true")
+		}
+		if d != 5*time.Second {
+			t.Errorf("got %s, want %s", d, 5*time.Second)
+		}
+	})
+
+	t.Run("HTTP-date form in the future", func(t *testing.T) {
+		when := time.Now().Add(1 * time.Minute)
+		r := &http.Response{Header: http.Header{
+			"Retry-After": []string{when.UTC().Format(http.TimeFormat)},
+		}}
+		d, ok := retryAfter(r)
+		if !ok {
+			t.Fatal("expected ok=true")
+		}
+		if d <= 0 || d > time.Minute {
+			t.Errorf("got %s, want a duration in (0, 1m]", d)
+		}
+	})
+
+	t.Run("HTTP-date form in the past", func(t *testing.T) {
+		when := time.Now().Add(-1 * time.Minute)
+		r := &http.Response{Header: http.Header{
+			"Retry-After": []string{when.UTC().Format(http.TimeFormat)},
+		}}
+		d, ok := retryAfter(r)
+		if !ok {
+			t.Fatal("expected ok=true")
+		}
+		if d != 0 {
+			t.Errorf("got %s, want 0 for a past date", d)
+		}
+	})
+
+	t.Run("unparsable value", func(t *testing.T) {
+		r := &http.Response{Header: http.Header{"Retry-After": []string{"not-a-date"}}}
+		if _, ok := retryAfter(r); ok {
+			t.Error("expected ok=false for an unparsable value")
+		}
+	})
+}
+
+func TestClientBackoff(t *testing.T) {
+	t.Run("honors Retry-After, capped at MaxDelay", func(t *testing.T) {
+		c := &Client{retryPolicy: RetryPolicy{MaxDelay: 2 * time.Second}}
+		r := &http.Response{Header: http.Header{"Retry-After": []string{"30"}}}
+
+		if d := c.backoff(r, 1); d != 2*time.Second {
+			t.Errorf("got %s, want %s", d, 2*time.Second)
+		}
+	})
+
+	t.Run("jittered exponential backoff stays within [0, cap]", func(t *testing.T) {
+		c := &Client{retryPolicy: RetryPolicy{
+			BaseDelay: 100 * time.Millisecond,
+			MaxDelay:  2 * time.Second,
+		}}
+
+		for attempt := 1; attempt <= 6; attempt++ {
+			max := c.retryPolicy.BaseDelay << (attempt - 1)
+			if max <= 0 || max > c.retryPolicy.MaxDelay {
+				max = c.retryPolicy.MaxDelay
+			}
+
+			for i := 0; i < 20; i++ {
+				d := c.backoff(nil, attempt)
+				if d < 0 || d > max {
+					t.Fatalf("attempt %d: got %s, want within [0, %s]", attempt, d, max)
+				}
+			}
+		}
+	})
+
+	t.Run("never exceeds MaxDelay even for large attempts", func(t *testing.T) {
+		c := &Client{retryPolicy: RetryPolicy{
+			BaseDelay: 100 * time.Millisecond,
+			MaxDelay:  time.Second,
+		}}
+
+		for i := 0; i < 20; i++ {
+			if d := c.backoff(nil, 20); d > time.Second {
+				t.Fatalf("got %s, want <= %s", d, time.Second)
+			}
+		}
+	})
+}
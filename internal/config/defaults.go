@@ -0,0 +1,19 @@
+package config
+
+import "github.com/spf13/viper"
+
+// SetDefaults registers the default value for every config key clockify-cli
+// reads, so a viper.Get* call never returns a surprising zero value just
+// because nothing set that key explicitly.
+func SetDefaults(v *viper.Viper) {
+	v.SetDefault("base-url", "https://api.clockify.me/api/v1")
+	v.SetDefault("debug", false)
+	v.SetDefault("log-format", "text")
+	v.SetDefault("log-level", "info")
+	v.SetDefault("interactive", false)
+	v.SetDefault("allow-name-for-id", false)
+
+	v.SetDefault("retry.max-attempts", 3)
+	v.SetDefault("retry.base-delay", "500ms")
+	v.SetDefault("retry.max-delay", "10s")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// legacyFileName is where clockify-cli historically read its config from,
+// before the switch to the XDG base directory convention.
+const legacyFileName = ".clockify-cli.yaml"
+
+// FilePath resolves which config file clockify-cli should read/write,
+// checking in order: $XDG_CONFIG_HOME/clockify-cli/config.yaml,
+// ~/.config/clockify-cli/config.yaml, then the legacy ~/.clockify-cli.yaml.
+// If a config is found only at the legacy path, it is migrated to the XDG
+// location before returning. If none exists yet, the XDG location is
+// returned so a new config file is created there.
+func FilePath() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	xdgHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgHome == "" {
+		xdgHome = filepath.Join(home, ".config")
+	}
+	xdgPath := filepath.Join(xdgHome, "clockify-cli", "config.yaml")
+
+	if _, err := os.Stat(xdgPath); err == nil {
+		return xdgPath, nil
+	}
+
+	dotConfigPath := filepath.Join(home, ".config", "clockify-cli", "config.yaml")
+	if _, err := os.Stat(dotConfigPath); err == nil {
+		return dotConfigPath, nil
+	}
+
+	legacyPath := filepath.Join(home, legacyFileName)
+	if _, err := os.Stat(legacyPath); err == nil {
+		if err := migrate(legacyPath, xdgPath); err != nil {
+			return legacyPath, err
+		}
+		return xdgPath, nil
+	}
+
+	return xdgPath, nil
+}
+
+// migrate copies the legacy config file to dest and removes the original.
+func migrate(legacyPath, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	b, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(dest, b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Remove(legacyPath)
+}
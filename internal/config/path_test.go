@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+func TestMigrate(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "legacy.yaml")
+	dest := filepath.Join(dir, "nested", "config.yaml")
+	want := []byte("token: abc\n")
+
+	if err := os.WriteFile(legacy, want, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrate(legacy, dest); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read dest: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("dest content = %q, want %q", got, want)
+	}
+
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("expected the legacy file to be removed after migration")
+	}
+}
+
+func TestFilePath(t *testing.T) {
+	// go-homedir caches the resolved home directory at the package level,
+	// which would leak the first subtest's $HOME into the rest since they
+	// each set it to a different temp dir. Disable that cache for the
+	// duration of this test.
+	homedir.DisableCache = true
+	t.Cleanup(func() { homedir.DisableCache = false })
+
+	t.Run("uses the XDG path when it already exists", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		xdgHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+		want := filepath.Join(xdgHome, "clockify-cli", "config.yaml")
+		if err := os.MkdirAll(filepath.Dir(want), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(want, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := FilePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("migrates a legacy-only config to the XDG location", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		xdgHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+		legacy := filepath.Join(home, legacyFileName)
+		if err := os.WriteFile(legacy, []byte("token: abc\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		want := filepath.Join(xdgHome, "clockify-cli", "config.yaml")
+		got, err := FilePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+
+		if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+			t.Error("expected the legacy file to have been migrated away")
+		}
+		if _, err := os.Stat(want); err != nil {
+			t.Errorf("expected a migrated config at %s: %v", want, err)
+		}
+	})
+
+	t.Run("defaults to the XDG location when nothing exists yet", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		xdgHome := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdgHome)
+
+		want := filepath.Join(xdgHome, "clockify-cli", "config.yaml")
+		got, err := FilePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+}
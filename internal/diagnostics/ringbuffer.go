@@ -0,0 +1,51 @@
+package diagnostics
+
+import (
+	"strings"
+	"sync"
+)
+
+// RingBuffer is an io.Writer that keeps only the last N lines written to
+// it, discarding older ones. It is safe for concurrent use.
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	max   int
+}
+
+// NewRingBuffer creates a RingBuffer holding at most max lines.
+func NewRingBuffer(max int) *RingBuffer {
+	return &RingBuffer{max: max}
+}
+
+// Write implements io.Writer, splitting p on newlines and keeping only the
+// most recent lines up to the buffer's capacity.
+func (b *RingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		b.lines = append(b.lines, line)
+	}
+
+	if len(b.lines) > b.max {
+		b.lines = b.lines[len(b.lines)-b.max:]
+	}
+
+	return len(p), nil
+}
+
+// Lines returns a copy of the lines currently held by the buffer, oldest
+// first.
+func (b *RingBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	out := make([]string, len(b.lines))
+	copy(out, b.lines)
+	return out
+}
@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingBufferWrite(t *testing.T) {
+	t.Run("keeps lines in order under capacity", func(t *testing.T) {
+		b := NewRingBuffer(3)
+
+		if _, err := b.Write([]byte("one\ntwo\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := b.Lines(), []string{"one", "two"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("drops the oldest lines once over capacity", func(t *testing.T) {
+		b := NewRingBuffer(2)
+
+		if _, err := b.Write([]byte("one\ntwo\nthree\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := b.Lines(), []string{"two", "three"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("eviction spans multiple writes", func(t *testing.T) {
+		b := NewRingBuffer(2)
+
+		for _, s := range []string{"one\n", "two\n", "three\n"} {
+			if _, err := b.Write([]byte(s)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got, want := b.Lines(), []string{"two", "three"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("ignores blank lines", func(t *testing.T) {
+		b := NewRingBuffer(10)
+
+		if _, err := b.Write([]byte("one\n\ntwo\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := b.Lines(), []string{"one", "two"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Write reports the number of bytes given", func(t *testing.T) {
+		b := NewRingBuffer(10)
+		p := []byte("hello\n")
+
+		n, err := b.Write(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(p) {
+			t.Errorf("n = %d, want %d", n, len(p))
+		}
+	})
+
+	t.Run("Lines returns a copy", func(t *testing.T) {
+		b := NewRingBuffer(10)
+		_, _ = b.Write([]byte("one\n"))
+
+		lines := b.Lines()
+		lines[0] = "mutated"
+
+		if got := b.Lines(); got[0] != "one" {
+			t.Errorf("internal state was mutated through the returned slice: got %v", got)
+		}
+	})
+}